@@ -20,16 +20,54 @@ import (
 	"fmt"
 
 	"github.com/spf13/pflag"
+	"google.golang.org/grpc/grpclog"
 
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/bbr/config"
 	"sigs.k8s.io/gateway-api-inference-extension/pkg/common/observability/logging"
+	bbrgrpclog "sigs.k8s.io/gateway-api-inference-extension/pkg/common/observability/logging/grpclog"
 )
 
 const (
 	DefaultGrpcPort       = 9004
 	DefaultGrpcHealthPort = 9005
+
+	// DisableGRPCLogBridging is the sentinel value for --grpc-log-verbosity
+	// that leaves grpc's own default stderr logging untouched.
+	DisableGRPCLogBridging = -1
+
+	// ModeExtProc runs only the ext_proc gRPC server, for deployment as a
+	// lightweight sidecar next to Envoy.
+	ModeExtProc = "ext-proc"
+	// ModeMetricsOnly runs only the metrics (and pprof) HTTP server, for
+	// deployment as a separate metrics/pprof pod.
+	ModeMetricsOnly = "metrics-only"
+	// ModeAll runs both the ext_proc gRPC server and the metrics HTTP
+	// server in a single process. This is the default, all-in-one mode.
+	ModeAll = "all"
 )
 
+// subcommandModes maps the positional subcommand names accepted by the BBR
+// binary (e.g. "bbr ext-proc ...") to the --mode value they imply.
+var subcommandModes = map[string]string{
+	"ext-proc": ModeExtProc,
+	"metrics":  ModeMetricsOnly,
+}
+
+// ParseMode inspects args for a leading "ext-proc" or "metrics" subcommand
+// (as in "bbr ext-proc --grpc-port=9004") and returns the mode it implies
+// along with the remaining arguments to be flag-parsed. If args has no
+// recognized subcommand, it returns the empty mode and args unchanged, and
+// the --mode flag (or its "all" default) governs instead.
+func ParseMode(args []string) (mode string, rest []string) {
+	if len(args) == 0 {
+		return "", args
+	}
+	if mode, ok := subcommandModes[args[0]]; ok {
+		return mode, args[1:]
+	}
+	return "", args
+}
+
 // Options contains the command-line configuration for the BBR server.
 type Options struct {
 	//
@@ -46,10 +84,15 @@ type Options struct {
 	EnablePprof         bool // Enables pprof handlers.
 	SecureServing       bool // Enables secure serving.
 	MetricsEndpointAuth bool // Enables authentication and authorization of the metrics endpoint.
+	GRPCLogVerbosity    int  // Bridges grpc library logs into the zap logger at this verbosity (0-2), or DisableGRPCLogBridging to leave them alone.
 	//
 	// Plugins.
 	//
 	PluginSpecs config.BBRPluginSpecs // Repeatable --plugin <type>:<name>[:<json>] flag values.
+	//
+	// Driver mode.
+	//
+	Mode string // One of ModeExtProc, ModeMetricsOnly, or ModeAll. Selects which listeners are started.
 }
 
 // NewOptions returns a new Options struct initialized with default values.
@@ -62,7 +105,23 @@ func NewOptions() *Options {
 		EnablePprof:         true,
 		SecureServing:       true,
 		MetricsEndpointAuth: true,
+		GRPCLogVerbosity:    DisableGRPCLogBridging,
+		Mode:                ModeAll,
+	}
+}
+
+// NewOptionsFromArgs returns a new Options struct initialized with default
+// values and, if args has a leading "ext-proc" or "metrics" subcommand (see
+// ParseMode), Mode set accordingly. It returns the remaining arguments,
+// which the caller should flag-parse with Options.AddFlags instead of args
+// itself so a subcommand isn't mistaken for a positional flag argument.
+func NewOptionsFromArgs(args []string) (*Options, []string) {
+	opts := NewOptions()
+	mode, rest := ParseMode(args)
+	if mode != "" {
+		opts.Mode = mode
 	}
+	return opts, rest
 }
 
 // AddFlags binds the Options fields to command-line flags on the given FlagSet.
@@ -85,6 +144,10 @@ func (opts *Options) AddFlags(fs *pflag.FlagSet) {
 		"Enables secure serving.")
 	fs.BoolVar(&opts.EnablePprof, "enable-pprof", opts.EnablePprof,
 		"Enables pprof handlers. Defaults to true. Set to false to disable pprof handlers.")
+	fs.IntVar(&opts.GRPCLogVerbosity, "grpc-log-verbosity", opts.GRPCLogVerbosity,
+		"Verbosity (0-2) at which to bridge grpc library logs into the app's zap logger. Defaults to -1, leaving grpc's own logging untouched.")
+	fs.StringVar(&opts.Mode, "mode", opts.Mode,
+		`Which listeners to start: "ext-proc" (gRPC only), "metrics-only" (metrics/pprof HTTP only), or "all". Defaults to "all".`)
 
 	fs.Var(&opts.PluginSpecs, "plugin", `Repeatable. --plugin <type>:<name>[:<json>]`)
 
@@ -93,34 +156,56 @@ func (opts *Options) AddFlags(fs *pflag.FlagSet) {
 }
 
 // Complete performs post-processing of parsed command-line arguments.
+//
+// Complete does not start the embedded logging.Options' periodic flush
+// goroutine; the server entrypoint must also call opts.Start(ctx) once
+// Complete succeeds, or buffered and rotated log writers are only flushed by
+// an explicit logger.Sync() elsewhere.
 func (opts *Options) Complete() error {
 	// Complete logging options.
-	return opts.Options.Complete()
+	if err := opts.Options.Complete(); err != nil {
+		return err
+	}
+
+	if opts.GRPCLogVerbosity != DisableGRPCLogBridging {
+		grpclog.SetLoggerV2(bbrgrpclog.NewLoggerV2(opts.Options.Logger(), opts.GRPCLogVerbosity))
+	}
+
+	return nil
 }
 
 // Validate checks the Options for invalid or conflicting values.
 func (opts *Options) Validate() error {
-	// Validate port ranges.
-	for _, pc := range []struct {
-		name string
-		port int
-	}{
-		{"grpc-port", opts.GRPCPort},
-		{"grpc-health-port", opts.GRPCHealthPort},
-		{"metrics-port", opts.MetricsPort},
-	} {
-		if pc.port < 1 || pc.port > 65535 {
-			return fmt.Errorf("invalid value %d for flag %q: must be between 1 and 65535", pc.port, pc.name)
+	switch opts.Mode {
+	case ModeExtProc, ModeMetricsOnly, ModeAll:
+	default:
+		return fmt.Errorf("invalid value %q for flag %q: must be one of %q, %q, or %q",
+			opts.Mode, "mode", ModeExtProc, ModeMetricsOnly, ModeAll)
+	}
+
+	// Ports that are actually bound in the chosen mode.
+	boundPorts := map[string]int{}
+	if opts.Mode != ModeMetricsOnly {
+		boundPorts["grpc-port"] = opts.GRPCPort
+		boundPorts["grpc-health-port"] = opts.GRPCHealthPort
+	}
+	if opts.Mode != ModeExtProc {
+		boundPorts["metrics-port"] = opts.MetricsPort
+	}
+
+	// Validate port ranges, but only for ports this mode will actually bind.
+	for name, port := range boundPorts {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("invalid value %d for flag %q: must be between 1 and 65535", port, name)
 		}
 	}
 
-	// Validate that the three server ports do not collide.
-	ports := map[int]string{
-		opts.GRPCPort:       "grpc-port",
-		opts.GRPCHealthPort: "grpc-health-port",
-		opts.MetricsPort:    "metrics-port",
+	// Validate that the bound ports do not collide with each other.
+	ports := map[int]string{}
+	for name, port := range boundPorts {
+		ports[port] = name
 	}
-	if len(ports) < 3 {
+	if len(ports) < len(boundPorts) {
 		return fmt.Errorf("port conflict: grpc-port (%d), grpc-health-port (%d), and metrics-port (%d) must all be different",
 			opts.GRPCPort, opts.GRPCHealthPort, opts.MetricsPort)
 	}
@@ -130,5 +215,9 @@ func (opts *Options) Validate() error {
 		return err
 	}
 
+	if opts.GRPCLogVerbosity != DisableGRPCLogBridging && (opts.GRPCLogVerbosity < 0 || opts.GRPCLogVerbosity > 2) {
+		return fmt.Errorf("invalid value %d for flag %q: must be between 0 and 2, or %d to disable", opts.GRPCLogVerbosity, "grpc-log-verbosity", DisableGRPCLogBridging)
+	}
+
 	return nil
 }
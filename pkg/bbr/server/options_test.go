@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantMode string
+		wantRest []string
+	}{
+		{"ext-proc subcommand", []string{"ext-proc", "--grpc-port=9004"}, ModeExtProc, []string{"--grpc-port=9004"}},
+		{"metrics subcommand", []string{"metrics", "--metrics-port=9090"}, ModeMetricsOnly, []string{"--metrics-port=9090"}},
+		{"no subcommand", []string{"--mode=all"}, "", []string{"--mode=all"}},
+		{"no args", []string{}, "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, rest := ParseMode(tt.args)
+			if mode != tt.wantMode {
+				t.Errorf("ParseMode(%v) mode = %q, want %q", tt.args, mode, tt.wantMode)
+			}
+			if !reflect.DeepEqual(rest, tt.wantRest) {
+				t.Errorf("ParseMode(%v) rest = %v, want %v", tt.args, rest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestNewOptionsFromArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantMode string
+	}{
+		{"ext-proc subcommand sets Mode", []string{"ext-proc", "--grpc-port=9004"}, ModeExtProc},
+		{"metrics subcommand sets Mode", []string{"metrics"}, ModeMetricsOnly},
+		{"no subcommand keeps the default Mode", []string{"--mode=metrics-only"}, ModeAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, _ := NewOptionsFromArgs(tt.args)
+			if opts.Mode != tt.wantMode {
+				t.Errorf("NewOptionsFromArgs(%v) Mode = %q, want %q", tt.args, opts.Mode, tt.wantMode)
+			}
+		})
+	}
+}
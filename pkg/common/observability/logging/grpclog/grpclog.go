@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package grpclog adapts the module's zap logger to grpc's grpclog.LoggerV2
+// interface so that gRPC library logging is emitted through the same zap
+// core as the rest of BBR, instead of going directly to stderr.
+package grpclog
+
+import (
+	uberzap "go.uber.org/zap"
+	"google.golang.org/grpc/grpclog"
+)
+
+// zapLoggerV2 adapts a *uberzap.Logger to grpclog.LoggerV2.
+type zapLoggerV2 struct {
+	logger    *uberzap.SugaredLogger
+	verbosity int // grpc verbosity level (0-2) this logger is enabled for.
+}
+
+// NewLoggerV2 returns a grpclog.LoggerV2 that forwards grpc's Info/Warning/Error/Fatal
+// calls to logger, and reports itself enabled for grpc verbosity levels up to and
+// including verbosity (grpc's convention is 0-2, higher is more verbose).
+func NewLoggerV2(logger *uberzap.Logger, verbosity int) grpclog.LoggerV2 {
+	return &zapLoggerV2{
+		logger:    logger.Sugar(),
+		verbosity: verbosity,
+	}
+}
+
+func (l *zapLoggerV2) Info(args ...interface{}) {
+	l.logger.Info(args...)
+}
+
+func (l *zapLoggerV2) Infoln(args ...interface{}) {
+	l.logger.Info(args...)
+}
+
+func (l *zapLoggerV2) Infof(format string, args ...interface{}) {
+	l.logger.Infof(format, args...)
+}
+
+func (l *zapLoggerV2) Warning(args ...interface{}) {
+	l.logger.Warn(args...)
+}
+
+func (l *zapLoggerV2) Warningln(args ...interface{}) {
+	l.logger.Warn(args...)
+}
+
+func (l *zapLoggerV2) Warningf(format string, args ...interface{}) {
+	l.logger.Warnf(format, args...)
+}
+
+func (l *zapLoggerV2) Error(args ...interface{}) {
+	l.logger.Error(args...)
+}
+
+func (l *zapLoggerV2) Errorln(args ...interface{}) {
+	l.logger.Error(args...)
+}
+
+func (l *zapLoggerV2) Errorf(format string, args ...interface{}) {
+	l.logger.Errorf(format, args...)
+}
+
+func (l *zapLoggerV2) Fatal(args ...interface{}) {
+	l.logger.Fatal(args...)
+}
+
+func (l *zapLoggerV2) Fatalln(args ...interface{}) {
+	l.logger.Fatal(args...)
+}
+
+func (l *zapLoggerV2) Fatalf(format string, args ...interface{}) {
+	l.logger.Fatalf(format, args...)
+}
+
+// V reports whether verbosity level v is enabled. grpc calls this with 0-2,
+// where 2 is the most verbose (debug-only chatter).
+func (l *zapLoggerV2) V(v int) bool {
+	return v <= l.verbosity
+}
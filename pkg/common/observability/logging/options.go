@@ -17,16 +17,32 @@ limitations under the License.
 package logging
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	uberzap "go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+	logsapi "k8s.io/component-base/logs/api/v1"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
 
 const (
 	ZapLogLevelFlagName = "zap-log-level"
+
+	// LogFormatText and LogFormatJSON are the accepted values for --log-format.
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+
+	// defaultInfoBufferFlushInterval is how often the buffered info-level
+	// syncer is flushed to its underlying writer when a buffer size is set.
+	defaultInfoBufferFlushInterval = 5 * time.Second
 )
 
 // Options contains logging configuration for command-line flags.
@@ -34,15 +50,56 @@ type Options struct {
 	LogVerbosity int         // Number for the log level verbosity.
 	ZapOptions   zap.Options // Zap logging options.
 
+	// KubeLogs is the k8s component-base logging configuration backing
+	// --logging-format and --log-flush-frequency. Complete() runs it through
+	// logsapi.ValidateAndApply, so klog-based logging from dependencies such
+	// as client-go and controller-runtime is governed by the same format and
+	// flush settings as the rest of the ecosystem, instead of reimplementing
+	// that behavior with hand-rolled flags.
+	//
+	// This deliberately configures a second, independent logging backend
+	// rather than replacing ZapOptions/zap.NewRaw below: BBR's own structured
+	// logging (stream-splitting, info buffering, file rotation, the grpclog
+	// bridge, --log-vmodule) is built on zapcore and has no klog equivalent,
+	// while the klog-based logging produced by imported k8s libraries has no
+	// zapcore equivalent. --logging-format (and its deprecated alias
+	// --log-format) also selects the encoding of the zap logger, and
+	// --log-flush-frequency also sets the interval at which Start() syncs it;
+	// the zap logger is further controlled by -v, --log-split-stream,
+	// --log-info-buffer-size and --log-file*, below.
+	KubeLogs *logsapi.LoggingConfiguration
+
+	LogSplitStream    bool   // Routes error-and-above records to stderr and the rest to stdout.
+	LogInfoBufferSize string // Quantity (e.g. "0", "4Ki", "1M") sizing the info-level write buffer. "0" disables buffering.
+
+	// On-disk rotation, backed by lumberjack.Logger. LogFile == "" disables rotation.
+	LogFile           string // Path to the rotating log file. Disabled when empty.
+	LogFileMaxSize    int    // Maximum size in megabytes of the log file before it gets rotated.
+	LogFileMaxAge     int    // Maximum number of days to retain old log files, based on the timestamp in their filename.
+	LogFileMaxBackups int    // Maximum number of old log files to retain.
+
+	// LogVModule holds the raw --log-vmodule flag value, e.g. "router=4,scorer=2,ext_proc=1".
+	// It mirrors klog's vmodule semantics but is keyed by logger name (commonly a
+	// plugin name from config.BBRPluginSpecs) rather than by source file.
+	LogVModule string
+	// VModule is LogVModule parsed by Complete() into per-logger-name verbosity overrides.
+	VModule map[string]int
+
 	// internal
-	fs *pflag.FlagSet // FlagSet used in AddFlags() and consulted in Complete()
+	fs     *pflag.FlagSet  // FlagSet used in AddFlags() and consulted in Complete()
+	logger *uberzap.Logger // Logger built by Complete(); periodically flushed by Start().
 }
 
 // NewOptions returns a new Options struct initialized with default values.
 func NewOptions() *Options {
 	return &Options{
-		LogVerbosity: DEFAULT,
-		ZapOptions:   zap.Options{Development: true},
+		LogVerbosity:      DEFAULT,
+		ZapOptions:        zap.Options{Development: true},
+		KubeLogs:          logsapi.NewLoggingConfiguration(),
+		LogInfoBufferSize: "0",
+		LogFileMaxSize:    100,
+		LogFileMaxAge:     28,
+		LogFileMaxBackups: 10,
 	}
 }
 
@@ -55,6 +112,27 @@ func (opts *Options) AddFlags(fs *pflag.FlagSet) {
 
 	fs.IntVarP(&opts.LogVerbosity, "v", "v", opts.LogVerbosity,
 		"Number for the log level verbosity.")
+	fs.StringVar(&opts.KubeLogs.Format, "logging-format", opts.KubeLogs.Format,
+		`Log encoding to use. One of "text" or "json". "json" is recommended for production log-aggregation pipelines.`)
+	fs.StringVar(&opts.KubeLogs.Format, "log-format", opts.KubeLogs.Format,
+		`Deprecated: use --logging-format instead.`)
+	fs.MarkDeprecated("log-format", "use --logging-format instead")
+	fs.BoolVar(&opts.LogSplitStream, "log-split-stream", opts.LogSplitStream,
+		"Splits log output across streams: error-and-above records go to stderr, everything else goes to stdout.")
+	fs.StringVar(&opts.LogInfoBufferSize, "log-info-buffer-size", opts.LogInfoBufferSize,
+		`Size of an in-memory write buffer for info-level log records (e.g. "0", "4Ki", "1M"), flushed when full and periodically. "0" disables buffering. Requires --log-split-stream.`)
+	fs.DurationVar(&opts.KubeLogs.FlushFrequency.Duration, "log-flush-frequency", opts.KubeLogs.FlushFrequency.Duration,
+		"Maximum time between log flushes, mirroring the k8s component-base logs convention.")
+	fs.StringVar(&opts.LogFile, "log-file", opts.LogFile,
+		"If set, also writes logs to this file, rotated via lumberjack. Useful for local retention when cluster log shipping is unavailable.")
+	fs.IntVar(&opts.LogFileMaxSize, "log-file-max-size", opts.LogFileMaxSize,
+		"Maximum size in megabytes of the log file before it gets rotated.")
+	fs.IntVar(&opts.LogFileMaxAge, "log-file-max-age", opts.LogFileMaxAge,
+		"Maximum number of days to retain old rotated log files.")
+	fs.IntVar(&opts.LogFileMaxBackups, "log-file-max-backups", opts.LogFileMaxBackups,
+		"Maximum number of old rotated log files to retain.")
+	fs.StringVar(&opts.LogVModule, "log-vmodule", opts.LogVModule,
+		`Comma-separated list of name=verbosity pairs (e.g. "router=4,scorer=2") overriding -v per logger name, mirroring klog's vmodule.`)
 
 	// Bind zap flags (zap expects a standard Go FlagSet; pflag.FlagSet is not compatible).
 	gofs := flag.NewFlagSet("zap", flag.ExitOnError)
@@ -63,8 +141,17 @@ func (opts *Options) AddFlags(fs *pflag.FlagSet) {
 }
 
 // Complete performs post-processing of parsed command-line arguments.
-// Derives the zap log level from the -v flag when --zap-log-level is not set explicitly.
+// Derives the zap log level from the -v flag when --zap-log-level is not set explicitly,
+// and wires the JSON encoding, stream-splitting, and info-buffering options into the
+// underlying zap core.
 func (opts *Options) Complete() error {
+	// Validates and applies KubeLogs, which governs klog-based logging from
+	// dependencies such as client-go and controller-runtime (the app's own
+	// zap-based logging, wired in below, is configured separately).
+	if err := logsapi.ValidateAndApply(opts.KubeLogs, nil); err != nil {
+		return err
+	}
+
 	zapLogLevelFlag := opts.fs.Lookup(ZapLogLevelFlagName)
 	if zapLogLevelFlag != nil && !zapLogLevelFlag.Changed {
 		// See https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/log/zap#Options.Level
@@ -72,6 +159,218 @@ func (opts *Options) Complete() error {
 		opts.ZapOptions.Level = uberzap.NewAtomicLevelAt(zapcore.Level(int8(lvl)))
 		zapLogLevelFlag.Changed = true
 	}
+
+	bufSize, err := parseQuantity(opts.LogInfoBufferSize)
+	if err != nil {
+		return err
+	}
+
+	vmodule, err := parseVModule(opts.LogVModule)
+	if err != nil {
+		return err
+	}
+	opts.VModule = vmodule
+
+	// A single WrapCore builds the whole chain so that each layer composes
+	// with the one before it instead of discarding it: the custom
+	// encoding/stream-splitting core (if requested) replaces the
+	// controller-runtime default as the base, and the file tee (if requested)
+	// wraps that. Appending these as separate WrapCore calls instead would
+	// apply them in order, and any one of them that builds a fresh core
+	// instead of wrapping its argument silently throws away everything
+	// before it.
+	//
+	// --log-vmodule overrides are NOT applied by wrapping this composed
+	// result: doing so would bypass the tee's own Check (see leafCore's
+	// doc comment), so each leaf below is individually made vmodule-aware
+	// as it's built instead.
+	opts.ZapOptions.ZapOpts = append(opts.ZapOptions.ZapOpts, uberzap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		result := core
+
+		if opts.KubeLogs.Format == LogFormatJSON || opts.LogSplitStream || bufSize > 0 {
+			result = opts.buildEncodedCore(bufSize)
+		} else if len(opts.VModule) > 0 {
+			result = &leafCore{Core: core, opts: opts}
+		}
+
+		if opts.LogFile != "" {
+			result = zapcore.NewTee(result, opts.buildFileCore())
+		}
+
+		return result
+	}))
+
+	opts.logger = zap.NewRaw(zap.UseFlagOptions(&opts.ZapOptions))
+	return nil
+}
+
+// buildEncodedCore builds the stdout/stderr core implied by KubeLogs.Format,
+// LogSplitStream and bufSize, replacing the controller-runtime zap default.
+// Each returned leaf is wrapped in leafCore, so --log-vmodule overrides are
+// honored regardless of which leaf(s) a record is routed to.
+func (opts *Options) buildEncodedCore(bufSize int64) zapcore.Core {
+	encoderCfg := uberzap.NewProductionEncoderConfig()
+	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	var encoder zapcore.Encoder
+	if opts.KubeLogs.Format == LogFormatJSON {
+		encoder = zapcore.NewJSONEncoder(encoderCfg)
+	} else {
+		encoder = zapcore.NewConsoleEncoder(encoderCfg)
+	}
+
+	if !opts.LogSplitStream {
+		return &leafCore{Core: zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), zapcore.DebugLevel), opts: opts}
+	}
+
+	infoSink := zapcore.WriteSyncer(zapcore.Lock(os.Stdout))
+	var buffered *zapcore.BufferedWriteSyncer
+	if bufSize > 0 {
+		buffered = &zapcore.BufferedWriteSyncer{
+			WS:            infoSink,
+			Size:          int(bufSize),
+			FlushInterval: defaultInfoBufferFlushInterval,
+		}
+		infoSink = buffered
+	}
+
+	errorCore := &leafCore{
+		Core:    zapcore.NewCore(encoder, zapcore.Lock(os.Stderr), zapcore.DebugLevel),
+		opts:    opts,
+		routing: func(l zapcore.Level) bool { return l >= zapcore.ErrorLevel },
+	}
+	infoCore := &leafCore{
+		Core:    zapcore.NewCore(encoder, infoSink, zapcore.DebugLevel),
+		opts:    opts,
+		routing: func(l zapcore.Level) bool { return l < zapcore.ErrorLevel },
+	}
+
+	if buffered != nil {
+		// zapcore.NewTee never calls Sync on one core as a side effect of
+		// writing to another, so the buffered info sink would otherwise only
+		// be flushed by its own FlushInterval timer or an explicit
+		// logger.Sync(). Flush it eagerly whenever an error-level record is
+		// written so buffered info context around an error isn't lost.
+		errorCore.flush = buffered.Sync
+	}
+
+	return zapcore.NewTee(errorCore, infoCore)
+}
+
+// leafCore wraps a single zapcore.Core leaf -- never an already-composed
+// zapcore.NewTee -- replacing its gating with a decision that considers
+// opts.LevelEnablerForName(entry.LoggerName) (so a --log-vmodule override can
+// raise or lower this leaf's effective level) together with routing, an
+// optional predicate restricting which levels this leaf accepts (e.g.
+// "error-and-above"). flush, if set, runs after every record this leaf
+// writes.
+//
+// This must be applied per leaf, before composing leaves with
+// zapcore.NewTee, not to the tee as a whole: a tee's Write fans out to every
+// sub-core unconditionally -- the routing happens in the tee's own Check,
+// which calls each sub-core's Check individually to decide whether to add
+// it. Wrapping the composed tee and bypassing its Check (as Write-forwarding
+// gating wrappers normally do, see Write below) would skip that per-core
+// routing decision and write every record to every leaf.
+type leafCore struct {
+	zapcore.Core
+	opts    *Options
+	routing func(zapcore.Level) bool
+	flush   func() error
+}
+
+func (c *leafCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.opts.LevelEnablerForName(entry.LoggerName).Enabled(entry.Level) {
+		return ce
+	}
+	if c.routing != nil && !c.routing(entry.Level) {
+		return ce
+	}
+	return ce.AddCore(entry, c)
+}
+
+// Enabled reports whether lvl can pass Check for at least one logger name,
+// under the global level or under any --log-vmodule override; Check then
+// makes the per-name decision.
+func (c *leafCore) Enabled(lvl zapcore.Level) bool {
+	if c.routing != nil && !c.routing(lvl) {
+		return false
+	}
+	if c.opts.LevelEnablerForName("").Enabled(lvl) {
+		return true
+	}
+	for _, v := range c.opts.VModule {
+		if zapcore.Level(int8(-1 * v)).Enabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+// With keeps the leafCore around the child core, so loggers derived with
+// With are gated, routed and flushed like their parent.
+func (c *leafCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.Core = c.Core.With(fields)
+	return &clone
+}
+
+func (c *leafCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(entry, fields)
+	if c.flush != nil {
+		if ferr := c.flush(); ferr != nil && err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+// buildFileCore builds the lumberjack-backed core for --log-file, wrapped in
+// leafCore so --log-vmodule overrides are honored for file output too.
+func (opts *Options) buildFileCore() zapcore.Core {
+	fileEncoderCfg := uberzap.NewProductionEncoderConfig()
+	fileEncoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	fileWS := zapcore.AddSync(&lumberjack.Logger{
+		Filename:   opts.LogFile,
+		MaxSize:    opts.LogFileMaxSize,
+		MaxAge:     opts.LogFileMaxAge,
+		MaxBackups: opts.LogFileMaxBackups,
+	})
+	return &leafCore{Core: zapcore.NewCore(zapcore.NewJSONEncoder(fileEncoderCfg), fileWS, zapcore.DebugLevel), opts: opts}
+}
+
+// Logger returns the *zap.Logger built by Complete(). It is nil until Complete() runs.
+func (opts *Options) Logger() *uberzap.Logger {
+	return opts.logger
+}
+
+// Start begins a background goroutine that calls logger.Sync() every
+// --log-flush-frequency, ensuring buffered and rotated-file writers are
+// flushed even under light traffic. The goroutine exits, performing a final
+// sync, when ctx is done. Complete() must be called first.
+//
+// Start has no effect on its own: callers (e.g. the BBR server's entrypoint)
+// must invoke it once after Complete() succeeds, with a ctx that lives for
+// the process's run and is cancelled on shutdown, or buffered/rotated log
+// writers will only ever be flushed by an explicit logger.Sync() elsewhere.
+func (opts *Options) Start(ctx context.Context) error {
+	if opts.logger == nil {
+		return fmt.Errorf("logging: Start called before Complete")
+	}
+
+	go func() {
+		ticker := time.NewTicker(opts.KubeLogs.FlushFrequency.Duration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				_ = opts.logger.Sync()
+			case <-ctx.Done():
+				_ = opts.logger.Sync()
+				return
+			}
+		}
+	}()
+
 	return nil
 }
 
@@ -81,5 +380,116 @@ func (opts *Options) Validate() error {
 	if opts.LogVerbosity < 0 {
 		return ErrInvalidLogVerbosity
 	}
+
+	if opts.KubeLogs.Format != LogFormatText && opts.KubeLogs.Format != LogFormatJSON {
+		return fmt.Errorf("invalid value %q for flag \"logging-format\": must be %q or %q", opts.KubeLogs.Format, LogFormatText, LogFormatJSON)
+	}
+
+	bufSize, err := parseQuantity(opts.LogInfoBufferSize)
+	if err != nil {
+		return fmt.Errorf("invalid value %q for flag \"log-info-buffer-size\": %w", opts.LogInfoBufferSize, err)
+	}
+	if bufSize > 0 && !opts.LogSplitStream {
+		return fmt.Errorf("--log-info-buffer-size requires --log-split-stream to be set")
+	}
+
+	if opts.KubeLogs.FlushFrequency.Duration <= 0 {
+		return fmt.Errorf("invalid value %v for flag \"log-flush-frequency\": must be positive", opts.KubeLogs.FlushFrequency.Duration)
+	}
+
+	if opts.LogFile != "" {
+		for _, lc := range []struct {
+			name  string
+			value int
+		}{
+			{"log-file-max-size", opts.LogFileMaxSize},
+			{"log-file-max-age", opts.LogFileMaxAge},
+			{"log-file-max-backups", opts.LogFileMaxBackups},
+		} {
+			if lc.value < 0 {
+				return fmt.Errorf("invalid value %d for flag %q: must be non-negative", lc.value, lc.name)
+			}
+		}
+	}
+
+	if _, err := parseVModule(opts.LogVModule); err != nil {
+		return fmt.Errorf("invalid value %q for flag \"log-vmodule\": %w", opts.LogVModule, err)
+	}
+
 	return nil
 }
+
+// LevelEnablerForName returns the zapcore.LevelEnabler that should gate
+// records from the logger named name: its --log-vmodule override if one was
+// given, otherwise the global -v level.
+func (opts *Options) LevelEnablerForName(name string) zapcore.LevelEnabler {
+	if v, ok := opts.VModule[name]; ok {
+		return zapcore.Level(int8(-1 * v))
+	}
+	if opts.ZapOptions.Level != nil {
+		return opts.ZapOptions.Level
+	}
+	return zapcore.InfoLevel
+}
+
+// parseVModule parses a comma-separated "name=verbosity" list such as
+// "router=4,scorer=2,ext_proc=1" into a map, mirroring klog's vmodule syntax.
+func parseVModule(s string) (map[string]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	vmodule := map[string]int{}
+	for _, pair := range strings.Split(s, ",") {
+		name, levelStr, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("malformed entry %q: expected name=verbosity", pair)
+		}
+		level, err := strconv.Atoi(levelStr)
+		if err != nil || level < 0 {
+			return nil, fmt.Errorf("malformed verbosity in entry %q: must be a non-negative integer", pair)
+		}
+		vmodule[name] = level
+	}
+	return vmodule, nil
+}
+
+// parseQuantity parses a quantity string such as "0", "4Ki", or "1M" into a
+// number of bytes. Supported binary suffixes are Ki, Mi, Gi; supported decimal
+// suffixes are K, M, G. A bare number is interpreted as bytes.
+func parseQuantity(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	multipliers := []struct {
+		suffix string
+		factor int64
+	}{
+		{"Ki", 1 << 10},
+		{"Mi", 1 << 20},
+		{"Gi", 1 << 30},
+		{"K", 1000},
+		{"M", 1000 * 1000},
+		{"G", 1000 * 1000 * 1000},
+	}
+
+	numPart := s
+	factor := int64(1)
+	for _, m := range multipliers {
+		if len(s) > len(m.suffix) && s[len(s)-len(m.suffix):] == m.suffix {
+			numPart = s[:len(s)-len(m.suffix)]
+			factor = m.factor
+			break
+		}
+	}
+
+	value, err := strconv.ParseInt(numPart, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse quantity %q", s)
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("quantity %q must not be negative", s)
+	}
+	return value * factor, nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package logging
 
 import (
+	"bytes"
 	"flag"
 	"testing"
 
@@ -115,6 +116,181 @@ func TestComplete_ExplicitZapLevel(t *testing.T) {
 	}
 }
 
+func TestAddFlags_LoggingMode(t *testing.T) {
+	opts := NewOptions()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opts.AddFlags(fs)
+
+	for _, name := range []string{"log-format", "log-split-stream", "log-info-buffer-size"} {
+		if fs.Lookup(name) == nil {
+			t.Errorf("Expected %s flag to be added", name)
+		}
+	}
+
+	if opts.KubeLogs.Format != LogFormatText {
+		t.Errorf("Expected default KubeLogs.Format to be %q, got %q", LogFormatText, opts.KubeLogs.Format)
+	}
+}
+
+func TestValidate_LogFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		expectError bool
+	}{
+		{"text is valid", LogFormatText, false},
+		{"json is valid", LogFormatJSON, false},
+		{"unknown format is invalid", "xml", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := NewOptions()
+			opts.KubeLogs.Format = tt.format
+
+			err := opts.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidate_LogInfoBufferSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		bufferSize  string
+		splitStream bool
+		expectError bool
+	}{
+		{"zero without split stream is valid", "0", false, false},
+		{"non-zero without split stream is invalid", "4Ki", false, true},
+		{"non-zero with split stream is valid", "4Ki", true, false},
+		{"unparseable quantity is invalid", "4banana", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := NewOptions()
+			opts.LogInfoBufferSize = tt.bufferSize
+			opts.LogSplitStream = tt.splitStream
+
+			err := opts.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestAddFlags_KubeLogging(t *testing.T) {
+	opts := NewOptions()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opts.AddFlags(fs)
+
+	for _, name := range []string{"logging-format", "log-flush-frequency", "log-file", "log-file-max-size", "log-file-max-age", "log-file-max-backups"} {
+		if fs.Lookup(name) == nil {
+			t.Errorf("Expected %s flag to be added", name)
+		}
+	}
+
+	logFormatFlag := fs.Lookup("log-format")
+	if logFormatFlag == nil {
+		t.Fatal("Expected log-format flag to be added")
+	}
+	if !logFormatFlag.Deprecated {
+		t.Error("Expected --log-format to be marked deprecated in favor of --logging-format")
+	}
+}
+
+func TestValidate_LogFlushFrequency(t *testing.T) {
+	opts := NewOptions()
+	opts.KubeLogs.FlushFrequency.Duration = 0
+
+	if err := opts.Validate(); err == nil {
+		t.Error("Expected error for non-positive --log-flush-frequency but got nil")
+	}
+}
+
+func TestValidate_LogFileRotation(t *testing.T) {
+	opts := NewOptions()
+	opts.LogFile = "/var/log/bbr.log"
+	opts.LogFileMaxSize = -1
+
+	if err := opts.Validate(); err == nil {
+		t.Error("Expected error for negative --log-file-max-size but got nil")
+	}
+}
+
+func TestAddFlags_LogVModule(t *testing.T) {
+	opts := NewOptions()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opts.AddFlags(fs)
+
+	if fs.Lookup("log-vmodule") == nil {
+		t.Error("Expected log-vmodule flag to be added")
+	}
+}
+
+func TestComplete_LogVModule(t *testing.T) {
+	opts := NewOptions()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opts.AddFlags(fs)
+
+	if err := fs.Parse([]string{"--log-vmodule=router=4,scorer=2"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	if err := opts.Complete(); err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+
+	want := map[string]int{"router": 4, "scorer": 2}
+	if len(opts.VModule) != len(want) {
+		t.Fatalf("Expected VModule %v, got %v", want, opts.VModule)
+	}
+	for name, level := range want {
+		if opts.VModule[name] != level {
+			t.Errorf("Expected VModule[%q] = %d, got %d", name, level, opts.VModule[name])
+		}
+	}
+}
+
+func TestValidate_LogVModule(t *testing.T) {
+	tests := []struct {
+		name        string
+		vmodule     string
+		expectError bool
+	}{
+		{"empty is valid", "", false},
+		{"single entry is valid", "router=4", false},
+		{"multiple entries is valid", "router=4,scorer=2", false},
+		{"missing equals is invalid", "router", true},
+		{"non-numeric verbosity is invalid", "router=debug", true},
+		{"negative verbosity is invalid", "router=-1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := NewOptions()
+			opts.LogVModule = tt.vmodule
+
+			err := opts.Validate()
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
 func TestValidate(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -153,6 +329,41 @@ func TestValidate_ErrorMessage(t *testing.T) {
 	}
 }
 
+// TestLogVModule_RoutedThroughTee reproduces the stdout/stderr split built
+// by buildEncodedCore, but with buffers standing in for the OS streams, and
+// asserts that an info record from a --log-vmodule-overridden logger is
+// written only to the stdout-equivalent buffer. leafCore must be applied to
+// each leaf before the tee composes them: wrapping the composed tee instead
+// bypasses per-leaf Check and would write the record to both buffers.
+func TestLogVModule_RoutedThroughTee(t *testing.T) {
+	opts := NewOptions()
+	opts.VModule = map[string]int{"router": 4}
+
+	var stdout, stderr bytes.Buffer
+	encoder := zapcore.NewConsoleEncoder(uberzap.NewProductionEncoderConfig())
+
+	errorCore := &leafCore{
+		Core:    zapcore.NewCore(encoder, zapcore.AddSync(&stderr), zapcore.DebugLevel),
+		opts:    opts,
+		routing: func(l zapcore.Level) bool { return l >= zapcore.ErrorLevel },
+	}
+	infoCore := &leafCore{
+		Core:    zapcore.NewCore(encoder, zapcore.AddSync(&stdout), zapcore.DebugLevel),
+		opts:    opts,
+		routing: func(l zapcore.Level) bool { return l < zapcore.ErrorLevel },
+	}
+
+	logger := uberzap.New(zapcore.NewTee(errorCore, infoCore)).Named("router")
+	logger.Info("overridden router is noisy at info level")
+
+	if stdout.Len() == 0 {
+		t.Error("Expected the info record to be written to the stdout-equivalent buffer, got nothing")
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("Expected nothing written to the stderr-equivalent buffer, got %q", stderr.String())
+	}
+}
+
 func init() {
 	// Clear any global flags from other tests
 	flag.CommandLine = flag.NewFlagSet("", flag.ContinueOnError)